@@ -0,0 +1,343 @@
+// Command fen2png renders a FEN record into a chess diagram. It is a thin
+// wrapper around the fen2png library package: all FEN decoding, layout,
+// and rendering lives there so it can be embedded in other programs.
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vpatkov/fen2png/pkg/fen2png"
+)
+
+const helpMessage = `Usage: fen2png [options] <fen> <output-file>
+Options:
+    --size=<size>  Diagram size (height and width) in pixels (default: 400)
+    --bg=<color>   Background color as hexadecimal RRGGBB (default: FFFFFF)
+    --fg=<color>   Foreground color as hexadecimal RRGGBB (default: 000000)
+    --light-square=<color>  Light square color as hexadecimal RRGGBB
+    --dark-square=<color>   Dark square color as hexadecimal RRGGBB
+    --piece-white=<color>   White piece color as hexadecimal RRGGBB
+    --piece-black=<color>   Black piece color as hexadecimal RRGGBB
+    --piece-outline=<color> Piece outline color as hexadecimal RRGGBB
+    --theme=<name> Theme preset for the colors above: lichess-brown,
+                   chess.com-green, wikipedia, ic, blue
+    --format=<fmt> Output format: svg, png, bmp, tiff, or webp (default:
+                   inferred from <output-file>, falling back to png)
+    --grayscale    Output grayscale PNG
+    --base64       Base64 output
+    --coordinates  Show coordinates on the diagram
+    --flip         Flip the diagram
+    --auto-flip    Flip the diagram if Black to move
+    --highlight=<sq>[,<sq>...]  Highlight squares, e.g. e4,d5
+    --arrow=<from><to>[,<from><to>...]  Draw arrows, e.g. e2e4,g1f3
+    --last-move=<from><to>  Highlight a move's source and destination
+    --check        Paint the king in check, if any, from the FEN
+    --pgn          Treat <fen> as a PGN file name (or "-" for stdin) and
+                   render the FEN after every ply instead of one diagram
+    --output-dir=<dir>   With --pgn, write 001.png, 002.png, ... to dir
+    --gif                With --pgn, write a single animated GIF to
+                         <output-file> instead of separate frame files
+    --frame-delay=<centis>  With --pgn --gif, delay between frames in
+                            hundredths of a second (default: 50)
+Positional arguments:
+    <fen>          FEN record, or with --pgn, a PGN file name or "-"
+    <output-file>  Output file name or "-" for the stdout (omitted with
+                   --pgn --output-dir)
+`
+
+type cmdOptions struct {
+	fen2png.Options
+	base64     bool
+	fen        string
+	outputFile string
+	help       bool
+
+	pgn        bool
+	outputDir  string
+	gif        bool
+	frameDelay int
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fen2png: error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseHexColor(value string) (color.RGBA, error) {
+	hex, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return color.RGBA{
+		uint8((hex >> 16) & 0xff),
+		uint8((hex >> 8) & 0xff),
+		uint8(hex & 0xff),
+		0xff,
+	}, nil
+}
+
+func parseCmdLine(args []string) (opts *cmdOptions, err error) {
+	opts = &cmdOptions{Options: fen2png.DefaultOptions(), frameDelay: 50}
+
+	if len(args) == 0 {
+		opts.help = true
+		return opts, nil
+	}
+
+	for ; len(args) > 0 && strings.HasPrefix(args[0], "--"); args = args[1:] {
+		option, value, hasValue := strings.Cut(args[0], "=")
+		switch option {
+		case "--size":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.Size, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for option %q", option)
+			}
+		case "--bg", "--fg", "--light-square", "--dark-square", "--piece-white", "--piece-black", "--piece-outline":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			c, err := parseHexColor(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for option %q", option)
+			}
+			switch option {
+			case "--bg":
+				opts.Background = c
+			case "--fg":
+				opts.Foreground = c
+			case "--light-square":
+				opts.LightSquare = c
+			case "--dark-square":
+				opts.DarkSquare = c
+			case "--piece-white":
+				opts.PieceWhite = c
+			case "--piece-black":
+				opts.PieceBlack = c
+			case "--piece-outline":
+				opts.PieceOutline = c
+			}
+		case "--theme":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			if _, ok := fen2png.Themes[value]; !ok {
+				return nil, fmt.Errorf("invalid value for option %q", option)
+			}
+			opts.Theme = value
+		case "--format":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			switch value {
+			case "svg", "png", "bmp", "tiff", "webp":
+				opts.Format = value
+			default:
+				return nil, fmt.Errorf("invalid value for option %q", option)
+			}
+		case "--grayscale":
+			opts.Grayscale = true
+		case "--base64":
+			opts.base64 = true
+		case "--coordinates":
+			opts.Coordinates = true
+		case "--flip":
+			opts.Flip = true
+		case "--auto-flip":
+			opts.AutoFlip = true
+		case "--highlight":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.Annotations.Highlight = append(opts.Annotations.Highlight, strings.Split(value, ",")...)
+		case "--arrow":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.Annotations.Arrow = append(opts.Annotations.Arrow, strings.Split(value, ",")...)
+		case "--last-move":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.Annotations.LastMove = value
+		case "--check":
+			opts.Annotations.Check = true
+		case "--pgn":
+			opts.pgn = true
+		case "--output-dir":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.outputDir = value
+		case "--gif":
+			opts.gif = true
+		case "--frame-delay":
+			if !hasValue {
+				return nil, fmt.Errorf("missing value for option %q", option)
+			}
+			opts.frameDelay, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for option %q", option)
+			}
+		case "--help":
+			opts.help = true
+			return opts, nil
+		default:
+			return nil, fmt.Errorf("unrecognized option: %q", option)
+		}
+	}
+
+	if len(args) < 1 {
+		return nil, fmt.Errorf("<fen> is required")
+	}
+	opts.fen = args[0]
+
+	if opts.pgn && opts.outputDir != "" && !opts.gif {
+		return opts, nil
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("<output-file> is required")
+	}
+	opts.outputFile = args[1]
+	return opts, nil
+}
+
+// runPGN parses opts.fen as a PGN file name (or "-" for stdin), renders
+// the FEN after every ply, and writes either a frame per ply into
+// opts.outputDir or a single animated GIF to opts.outputFile.
+func runPGN(opts *cmdOptions) error {
+	if opts.outputDir == "" && !opts.gif {
+		return fmt.Errorf("--pgn requires --output-dir or --gif")
+	}
+	if opts.outputDir != "" && opts.gif {
+		return fmt.Errorf("--output-dir and --gif are mutually exclusive")
+	}
+
+	var pgnText []byte
+	var err error
+	if opts.fen == "-" {
+		pgnText, err = io.ReadAll(os.Stdin)
+	} else {
+		pgnText, err = os.ReadFile(opts.fen)
+	}
+	if err != nil {
+		return err
+	}
+
+	fens, err := fen2png.FENsFromPGN(string(pgnText))
+	if err != nil {
+		return err
+	}
+	if len(fens) == 0 {
+		return fmt.Errorf("no moves found in PGN")
+	}
+
+	frames := make([]image.Image, len(fens))
+	for i, fen := range fens {
+		frames[i], err = fen2png.Render(fen, opts.Options)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.gif {
+		return writeGIF(opts, frames)
+	}
+	return writeFrameFiles(opts.outputDir, frames)
+}
+
+func writeFrameFiles(dir string, frames []image.Image) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, frame := range frames {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("%03d.png", i+1)))
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(f, frame); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGIF(opts *cmdOptions, frames []image.Image) error {
+	var output io.WriteCloser
+	if opts.outputFile == "-" {
+		output = os.Stdout
+	} else {
+		f, err := os.Create(opts.outputFile)
+		if err != nil {
+			return err
+		}
+		output = f
+	}
+	if opts.base64 {
+		output = base64.NewEncoder(base64.StdEncoding, output)
+	}
+
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.Draw(paletted, paletted.Bounds(), frame, frame.Bounds().Min, draw.Src)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, opts.frameDelay)
+	}
+	if err := gif.EncodeAll(output, anim); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+func main() {
+	opts, err := parseCmdLine(os.Args[1:])
+	check(err)
+	if opts.help {
+		fmt.Print(helpMessage)
+		os.Exit(0)
+	}
+
+	if opts.pgn {
+		check(runPGN(opts))
+		return
+	}
+
+	var output io.WriteCloser
+	if opts.outputFile == "-" {
+		output = os.Stdout
+	} else {
+		output, err = os.Create(opts.outputFile)
+		check(err)
+	}
+	if opts.base64 {
+		output = base64.NewEncoder(base64.StdEncoding, output)
+	}
+
+	err = fen2png.RenderTo(context.Background(), output, opts.fen, opts.outputFile, opts.Options)
+	check(err)
+	err = output.Close()
+	check(err)
+}
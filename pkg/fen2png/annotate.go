@@ -0,0 +1,156 @@
+package fen2png
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Annotations overlays information on top of a rendered diagram: arbitrary
+// highlighted squares, a highlighted last move, arrows between squares,
+// and a check indicator painted over the king in check. They are drawn
+// after the board itself, in the order square color, highlight, glyph,
+// arrows, so an arrow is always visible on top of a highlighted square.
+type Annotations struct {
+	// Highlight is a list of squares ("e4") to fill with HighlightColor.
+	Highlight []string
+	// Arrow is a list of four-character from/to square pairs ("e2e4") to
+	// draw as an arrow from the first square to the second.
+	Arrow []string
+	// LastMove, if set, is a four-character from/to square pair ("e2e4")
+	// whose squares are filled with LastMoveColor.
+	LastMove string
+	// Check, if set, paints CheckColor over the square of the side to
+	// move's king, but only if that king is actually in check.
+	Check bool
+
+	HighlightColor color.Color
+	ArrowColor     color.Color
+	LastMoveColor  color.Color
+	CheckColor     color.Color
+}
+
+func (a Annotations) highlightColor() color.Color {
+	if a.HighlightColor != nil {
+		return a.HighlightColor
+	}
+	return color.NRGBA{R: 0xff, G: 0xff, B: 0x00, A: 0x80}
+}
+
+func (a Annotations) arrowColor() color.Color {
+	if a.ArrowColor != nil {
+		return a.ArrowColor
+	}
+	return color.NRGBA{R: 0x00, G: 0x80, B: 0x00, A: 0xc0}
+}
+
+func (a Annotations) lastMoveColor() color.Color {
+	if a.LastMoveColor != nil {
+		return a.LastMoveColor
+	}
+	return color.NRGBA{R: 0xff, G: 0xff, B: 0x00, A: 0x60}
+}
+
+func (a Annotations) checkColor() color.Color {
+	if a.CheckColor != nil {
+		return a.CheckColor
+	}
+	return color.NRGBA{R: 0xff, G: 0x00, B: 0x00, A: 0x90}
+}
+
+// parseSquare parses algebraic square notation ("e4") into Board
+// coordinates, accounting for flip the same way DecodeFEN does.
+func parseSquare(sq string, flip bool) (y, x int, err error) {
+	if len(sq) != 2 || sq[0] < 'a' || sq[0] > 'h' || sq[1] < '1' || sq[1] > '8' {
+		return 0, 0, fmt.Errorf("invalid square %q", sq)
+	}
+	x = int(sq[0] - 'a')
+	y = 7 - int(sq[1]-'1')
+	if flip {
+		y, x = 7-y, 7-x
+	}
+	return y, x, nil
+}
+
+// arrowSquares is an arrow's endpoints in Board coordinates.
+type arrowSquares struct {
+	fromY, fromX, toY, toX int
+}
+
+// checkedKingSquare returns the board coordinates of the side-to-move's
+// king, and whether it is in check, reading the side to move from fen's
+// second field. The attack test runs on the unflipped position, since
+// pawn attack directions depend on which side rank 8 is.
+func checkedKingSquare(fen string, board Board, flip bool) (y, x int, ok bool) {
+	fields := strings.Fields(fen)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+	white := fields[1] == "w"
+	unflipped := board
+	if flip {
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				unflipped[y][x] = board[7-y][7-x]
+			}
+		}
+	}
+	y, x = findKing(unflipped, white)
+	if y < 0 {
+		return 0, 0, false
+	}
+	inCheck := isSquareAttacked(unflipped, y, x, !white)
+	if flip {
+		y, x = 7-y, 7-x
+	}
+	return y, x, inCheck
+}
+
+// resolve turns Annotations into the highlighted squares and arrows to
+// draw over fen's board, rejecting malformed square or move notation.
+func (a Annotations) resolve(fen string, board Board, flip bool) (highlights map[[2]int]color.Color, arrows []arrowSquares, err error) {
+	highlights = make(map[[2]int]color.Color)
+	for _, sq := range a.Highlight {
+		y, x, err := parseSquare(sq, flip)
+		if err != nil {
+			return nil, nil, err
+		}
+		highlights[[2]int{y, x}] = a.highlightColor()
+	}
+
+	if a.LastMove != "" {
+		if len(a.LastMove) != 4 {
+			return nil, nil, fmt.Errorf("invalid last move %q", a.LastMove)
+		}
+		for _, sq := range []string{a.LastMove[:2], a.LastMove[2:]} {
+			y, x, err := parseSquare(sq, flip)
+			if err != nil {
+				return nil, nil, err
+			}
+			highlights[[2]int{y, x}] = a.lastMoveColor()
+		}
+	}
+
+	if a.Check {
+		if y, x, ok := checkedKingSquare(fen, board, flip); ok {
+			highlights[[2]int{y, x}] = a.checkColor()
+		}
+	}
+
+	for _, spec := range a.Arrow {
+		if len(spec) != 4 {
+			return nil, nil, fmt.Errorf("invalid arrow %q", spec)
+		}
+		fromY, fromX, err := parseSquare(spec[:2], flip)
+		if err != nil {
+			return nil, nil, err
+		}
+		toY, toX, err := parseSquare(spec[2:], flip)
+		if err != nil {
+			return nil, nil, err
+		}
+		arrows = append(arrows, arrowSquares{fromY, fromX, toY, toX})
+	}
+
+	return highlights, arrows, nil
+}
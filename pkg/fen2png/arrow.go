@@ -0,0 +1,96 @@
+package fen2png
+
+import (
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// point is a floating-point pixel coordinate, used only to lay out the
+// quad and triangle an arrow is built from.
+type point struct{ x, y float64 }
+
+// blendOver alpha-composites src over dst, both already premultiplied as
+// returned by color.Color.RGBA.
+func blendOver(dst, src color.Color) color.Color {
+	dr, dg, db, da := dst.RGBA()
+	sr, sg, sb, sa := src.RGBA()
+	ia := 0xffff - sa
+	return color.RGBA64{
+		R: uint16(sr + dr*ia/0xffff),
+		G: uint16(sg + dg*ia/0xffff),
+		B: uint16(sb + db*ia/0xffff),
+		A: uint16(sa + da*ia/0xffff),
+	}
+}
+
+// fillPolygon fills a convex polygon with src, alpha-blending over
+// whatever dst already has. It's a plain scanline fill: good enough for
+// the quad and triangle an arrow is made of, not a general rasterizer.
+func fillPolygon(dst draw.Image, poly []point, src color.Color) {
+	bounds := dst.Bounds()
+	minY, maxY := poly[0].y, poly[0].y
+	for _, p := range poly {
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		if y < bounds.Min.Y || y >= bounds.Max.Y {
+			continue
+		}
+		fy := float64(y) + 0.5
+		var xs []float64
+		for i := range poly {
+			p0, p1 := poly[i], poly[(i+1)%len(poly)]
+			if (p0.y <= fy && p1.y > fy) || (p1.y <= fy && p0.y > fy) {
+				xs = append(xs, p0.x+(fy-p0.y)/(p1.y-p0.y)*(p1.x-p0.x))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0, x1 := int(math.Round(xs[i])), int(math.Round(xs[i+1]))
+			if x0 < bounds.Min.X {
+				x0 = bounds.Min.X
+			}
+			if x1 > bounds.Max.X {
+				x1 = bounds.Max.X
+			}
+			for x := x0; x < x1; x++ {
+				dst.Set(x, y, blendOver(dst.At(x, y), src))
+			}
+		}
+	}
+}
+
+// drawArrow draws a lichess/chess.com-style arrow from (x0,y0) to
+// (x1,y1): a thick shaft stopping short of the tip, plus a solid
+// triangular head, both scaled to cell (the board's square size).
+func drawArrow(dst draw.Image, x0, y0, x1, y1, cell float64, src color.Color) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	ux, uy := dx/length, dy/length
+	nx, ny := -uy, ux
+
+	headLen := cell * 0.35
+	headHalfWidth := cell * 0.22
+	shaftHalfWidth := cell * 0.08
+	if headLen > length {
+		headLen = length
+	}
+	shaftX, shaftY := x1-ux*headLen, y1-uy*headLen
+
+	fillPolygon(dst, []point{
+		{x0 + nx*shaftHalfWidth, y0 + ny*shaftHalfWidth},
+		{x0 - nx*shaftHalfWidth, y0 - ny*shaftHalfWidth},
+		{shaftX - nx*shaftHalfWidth, shaftY - ny*shaftHalfWidth},
+		{shaftX + nx*shaftHalfWidth, shaftY + ny*shaftHalfWidth},
+	}, src)
+	fillPolygon(dst, []point{
+		{x1, y1},
+		{shaftX + nx*headHalfWidth, shaftY + ny*headHalfWidth},
+		{shaftX - nx*headHalfWidth, shaftY - ny*headHalfWidth},
+	}, src)
+}
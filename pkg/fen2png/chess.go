@@ -0,0 +1,520 @@
+package fen2png
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// gameState tracks enough chess state to apply a stream of SAN moves and
+// produce the FEN after each ply: the board, whose turn it is, castling
+// rights, the en passant target square, and the two FEN move counters.
+type gameState struct {
+	board                                  Board
+	whiteToMove                            bool
+	castleWK, castleWQ, castleBK, castleBQ bool
+	epTargetY, epTargetX                   int // -1, -1 if none
+	halfmove, fullmove                     int
+}
+
+func newGameState() *gameState {
+	g := &gameState{
+		whiteToMove: true,
+		castleWK:    true,
+		castleWQ:    true,
+		castleBK:    true,
+		castleBQ:    true,
+		epTargetY:   -1,
+		epTargetX:   -1,
+		fullmove:    1,
+	}
+	backRank := []rune{'r', 'n', 'b', 'q', 'k', 'b', 'n', 'r'}
+	for x := 0; x < 8; x++ {
+		g.board[0][x] = backRank[x]
+		g.board[1][x] = 'p'
+		g.board[6][x] = 'P'
+		g.board[7][x] = unicode.ToUpper(backRank[x])
+	}
+	for y := 2; y < 6; y++ {
+		for x := 0; x < 8; x++ {
+			g.board[y][x] = ' '
+		}
+	}
+	return g
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func inBounds(y, x int) bool { return y >= 0 && y < 8 && x >= 0 && x < 8 }
+
+func sameColor(a, b rune) bool {
+	if a == ' ' || b == ' ' {
+		return false
+	}
+	return unicode.IsUpper(a) == unicode.IsUpper(b)
+}
+
+var rookDirs = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+var bishopDirs = [][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+var knightOffsets = [][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+var kingOffsets = [][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+
+// slidingReach reports whether a piece at (y,x) can reach (toY,toX) along
+// one of dirs without anything blocking the path (the destination square
+// itself may be occupied, by a capture).
+func slidingReach(board Board, y, x, toY, toX int, dirs [][2]int) bool {
+	for _, d := range dirs {
+		ny, nx := y+d[0], x+d[1]
+		for inBounds(ny, nx) {
+			if ny == toY && nx == toX {
+				return true
+			}
+			if board[ny][nx] != ' ' {
+				break
+			}
+			ny += d[0]
+			nx += d[1]
+		}
+	}
+	return false
+}
+
+// isSquareAttacked reports whether (y,x) is attacked by a piece of the
+// given color on board.
+func isSquareAttacked(board Board, y, x int, byWhite bool) bool {
+	pawn := 'p'
+	dy := -1
+	if byWhite {
+		pawn = 'P'
+		dy = 1
+	}
+	for _, dx := range []int{-1, 1} {
+		py, px := y+dy, x+dx
+		if inBounds(py, px) && board[py][px] == pawn {
+			return true
+		}
+	}
+
+	knight := 'N'
+	king := 'K'
+	rook, bishop, queen := 'R', 'B', 'Q'
+	if !byWhite {
+		knight, king, rook, bishop, queen = 'n', 'k', 'r', 'b', 'q'
+	}
+	for _, o := range knightOffsets {
+		ny, nx := y+o[0], x+o[1]
+		if inBounds(ny, nx) && board[ny][nx] == knight {
+			return true
+		}
+	}
+	for _, o := range kingOffsets {
+		ny, nx := y+o[0], x+o[1]
+		if inBounds(ny, nx) && board[ny][nx] == king {
+			return true
+		}
+	}
+	for _, d := range rookDirs {
+		ny, nx := y+d[0], x+d[1]
+		for inBounds(ny, nx) {
+			p := board[ny][nx]
+			if p != ' ' {
+				if p == rook || p == queen {
+					return true
+				}
+				break
+			}
+			ny += d[0]
+			nx += d[1]
+		}
+	}
+	for _, d := range bishopDirs {
+		ny, nx := y+d[0], x+d[1]
+		for inBounds(ny, nx) {
+			p := board[ny][nx]
+			if p != ' ' {
+				if p == bishop || p == queen {
+					return true
+				}
+				break
+			}
+			ny += d[0]
+			nx += d[1]
+		}
+	}
+	return false
+}
+
+func findKing(board Board, white bool) (y, x int) {
+	king := 'K'
+	if !white {
+		king = 'k'
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if board[y][x] == king {
+				return y, x
+			}
+		}
+	}
+	return -1, -1
+}
+
+func isInCheck(board Board, white bool) bool {
+	y, x := findKing(board, white)
+	if y < 0 {
+		return false
+	}
+	return isSquareAttacked(board, y, x, !white)
+}
+
+// canReach reports whether the piece on board at (y,x) pseudo-legally
+// reaches (toY,toX), given whether the SAN move was marked as a capture.
+func (g *gameState) canReach(y, x, toY, toX int, isCapture bool) bool {
+	piece := g.board[y][x]
+	switch unicode.ToUpper(piece) {
+	case 'N':
+		dy, dx := abs(toY-y), abs(toX-x)
+		return (dy == 1 && dx == 2) || (dy == 2 && dx == 1)
+	case 'B':
+		return slidingReach(g.board, y, x, toY, toX, bishopDirs)
+	case 'R':
+		return slidingReach(g.board, y, x, toY, toX, rookDirs)
+	case 'Q':
+		return slidingReach(g.board, y, x, toY, toX, rookDirs) || slidingReach(g.board, y, x, toY, toX, bishopDirs)
+	case 'K':
+		dy, dx := abs(toY-y), abs(toX-x)
+		return dy <= 1 && dx <= 1 && (dy != 0 || dx != 0)
+	case 'P':
+		return g.pawnReach(y, x, toY, toX, isCapture)
+	}
+	return false
+}
+
+func (g *gameState) pawnReach(y, x, toY, toX int, isCapture bool) bool {
+	white := unicode.IsUpper(g.board[y][x])
+	forward := -1
+	if !white {
+		forward = 1
+	}
+	if isCapture {
+		if toY != y+forward || abs(toX-x) != 1 {
+			return false
+		}
+		if g.board[toY][toX] != ' ' {
+			return true
+		}
+		return toY == g.epTargetY && toX == g.epTargetX
+	}
+	if toX != x {
+		return false
+	}
+	if toY == y+forward {
+		return g.board[toY][toX] == ' '
+	}
+	startY := 6
+	if !white {
+		startY = 1
+	}
+	if y == startY && toY == y+2*forward {
+		return g.board[y+forward][x] == ' ' && g.board[toY][toX] == ' '
+	}
+	return false
+}
+
+// candidatesTo returns every square holding a pieceChar piece of color
+// white that can pseudo-legally reach (toY,toX).
+func (g *gameState) candidatesTo(pieceChar byte, white bool, toY, toX int, isCapture bool) [][2]int {
+	var candidates [][2]int
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			p := g.board[y][x]
+			if p == ' ' || unicode.IsUpper(p) != white || unicode.ToUpper(p) != rune(pieceChar) {
+				continue
+			}
+			if g.canReach(y, x, toY, toX, isCapture) {
+				candidates = append(candidates, [2]int{y, x})
+			}
+		}
+	}
+	return candidates
+}
+
+// simulate applies a move to a copy of the board (Board is an array, so
+// this copies by value) without touching game state, so applySAN can
+// check whether it would leave the mover's own king in check.
+func (g *gameState) simulate(fromY, fromX, toY, toX int, promotion rune) Board {
+	board := g.board
+	piece := board[fromY][fromX]
+	white := unicode.IsUpper(piece)
+	if unicode.ToUpper(piece) == 'P' && toX != fromX && board[toY][toX] == ' ' {
+		board[fromY][toX] = ' '
+	}
+	board[toY][toX] = piece
+	board[fromY][fromX] = ' '
+	if promotion != 0 {
+		pr := unicode.ToUpper(promotion)
+		if !white {
+			pr = unicode.ToLower(pr)
+		}
+		board[toY][toX] = pr
+	}
+	return board
+}
+
+// executeMove performs the move chosen by applySAN for real, updating the
+// board and all FEN-relevant state (castling rights, en passant target,
+// halfmove clock, fullmove number, side to move).
+func (g *gameState) executeMove(fromY, fromX, toY, toX int, promotion rune) {
+	white := g.whiteToMove
+	piece := g.board[fromY][fromX]
+	isPawn := unicode.ToUpper(piece) == 'P'
+	enPassant := isPawn && toX != fromX && g.board[toY][toX] == ' '
+	captured := g.board[toY][toX]
+	if enPassant {
+		g.board[fromY][toX] = ' '
+		captured = 'x' // any non-' ' marks the halfmove clock reset below
+	}
+
+	g.board[toY][toX] = piece
+	g.board[fromY][fromX] = ' '
+	if promotion != 0 {
+		pr := unicode.ToUpper(promotion)
+		if !white {
+			pr = unicode.ToLower(pr)
+		}
+		g.board[toY][toX] = pr
+	}
+
+	if piece == 'K' {
+		g.castleWK, g.castleWQ = false, false
+	}
+	if piece == 'k' {
+		g.castleBK, g.castleBQ = false, false
+	}
+	for _, sq := range [][3]int{{7, 0, 1}, {7, 7, 2}, {0, 0, 3}, {0, 7, 4}} {
+		if (fromY == sq[0] && fromX == sq[1]) || (toY == sq[0] && toX == sq[1]) {
+			switch sq[2] {
+			case 1:
+				g.castleWQ = false
+			case 2:
+				g.castleWK = false
+			case 3:
+				g.castleBQ = false
+			case 4:
+				g.castleBK = false
+			}
+		}
+	}
+
+	g.epTargetY, g.epTargetX = -1, -1
+	if isPawn && abs(toY-fromY) == 2 {
+		g.epTargetY, g.epTargetX = (fromY+toY)/2, fromX
+	}
+
+	if isPawn || captured != ' ' {
+		g.halfmove = 0
+	} else {
+		g.halfmove++
+	}
+	if !white {
+		g.fullmove++
+	}
+	g.whiteToMove = !white
+}
+
+func (g *gameState) applyCastle(kingside bool) error {
+	white := g.whiteToMove
+	y := 7
+	if !white {
+		y = 0
+	}
+	canK, canQ := g.castleWK, g.castleWQ
+	if !white {
+		canK, canQ = g.castleBK, g.castleBQ
+	}
+
+	if kingside {
+		if !canK {
+			return fmt.Errorf("kingside castling not available")
+		}
+		if g.board[y][5] != ' ' || g.board[y][6] != ' ' {
+			return fmt.Errorf("kingside castling path blocked")
+		}
+		if isSquareAttacked(g.board, y, 4, !white) || isSquareAttacked(g.board, y, 5, !white) || isSquareAttacked(g.board, y, 6, !white) {
+			return fmt.Errorf("cannot castle kingside through check")
+		}
+		king, rook := g.board[y][4], g.board[y][7]
+		g.board[y][4], g.board[y][7] = ' ', ' '
+		g.board[y][6], g.board[y][5] = king, rook
+	} else {
+		if !canQ {
+			return fmt.Errorf("queenside castling not available")
+		}
+		if g.board[y][1] != ' ' || g.board[y][2] != ' ' || g.board[y][3] != ' ' {
+			return fmt.Errorf("queenside castling path blocked")
+		}
+		if isSquareAttacked(g.board, y, 4, !white) || isSquareAttacked(g.board, y, 3, !white) || isSquareAttacked(g.board, y, 2, !white) {
+			return fmt.Errorf("cannot castle queenside through check")
+		}
+		king, rook := g.board[y][4], g.board[y][0]
+		g.board[y][4], g.board[y][0] = ' ', ' '
+		g.board[y][2], g.board[y][3] = king, rook
+	}
+
+	if white {
+		g.castleWK, g.castleWQ = false, false
+	} else {
+		g.castleBK, g.castleBQ = false, false
+	}
+	g.epTargetY, g.epTargetX = -1, -1
+	g.halfmove++
+	if !white {
+		g.fullmove++
+	}
+	g.whiteToMove = !white
+	return nil
+}
+
+// applySAN parses a single SAN move token and applies it, disambiguating
+// it against the set of pseudo-legal moves that don't leave the mover's
+// own king in check.
+func (g *gameState) applySAN(tok string) error {
+	tok = strings.TrimRight(tok, "+#!?")
+	switch tok {
+	case "O-O", "0-0":
+		return g.applyCastle(true)
+	case "O-O-O", "0-0-0":
+		return g.applyCastle(false)
+	}
+
+	promotion := rune(0)
+	if i := strings.IndexByte(tok, '='); i >= 0 {
+		if i+1 >= len(tok) {
+			return fmt.Errorf("malformed promotion in %q", tok)
+		}
+		promotion = rune(tok[i+1])
+		tok = tok[:i]
+	}
+	pieceChar := byte('P')
+	if len(tok) > 0 && strings.ContainsRune("NBRQK", rune(tok[0])) {
+		pieceChar = tok[0]
+		tok = tok[1:]
+	}
+	capture := false
+	if i := strings.IndexByte(tok, 'x'); i >= 0 {
+		capture = true
+		tok = tok[:i] + tok[i+1:]
+	}
+	if len(tok) < 2 {
+		return fmt.Errorf("cannot parse move %q", tok)
+	}
+	dest := tok[len(tok)-2:]
+	disambig := tok[:len(tok)-2]
+	if dest[0] < 'a' || dest[0] > 'h' || dest[1] < '1' || dest[1] > '8' {
+		return fmt.Errorf("invalid destination square in %q", tok)
+	}
+	toX := int(dest[0] - 'a')
+	toY := 7 - int(dest[1]-'1')
+	fromFile, fromRank := -1, -1
+	for _, c := range disambig {
+		switch {
+		case c >= 'a' && c <= 'h':
+			fromFile = int(c - 'a')
+		case c >= '1' && c <= '8':
+			fromRank = 7 - int(c-'1')
+		}
+	}
+
+	white := g.whiteToMove
+	var legal [][2]int
+	for _, c := range g.candidatesTo(pieceChar, white, toY, toX, capture) {
+		if fromFile >= 0 && c[1] != fromFile {
+			continue
+		}
+		if fromRank >= 0 && c[0] != fromRank {
+			continue
+		}
+		if isInCheck(g.simulate(c[0], c[1], toY, toX, promotion), white) {
+			continue
+		}
+		legal = append(legal, c)
+	}
+	switch len(legal) {
+	case 0:
+		return fmt.Errorf("no legal move found for %q", tok)
+	case 1:
+		g.executeMove(legal[0][0], legal[0][1], toY, toX, promotion)
+		return nil
+	default:
+		return fmt.Errorf("ambiguous move %q", tok)
+	}
+}
+
+// fen encodes the current position as a FEN record.
+func (g *gameState) fen() string {
+	var sb strings.Builder
+	for y := 0; y < 8; y++ {
+		empty := 0
+		for x := 0; x < 8; x++ {
+			p := g.board[y][x]
+			if p == ' ' {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteRune(p)
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+		if y < 7 {
+			sb.WriteByte('/')
+		}
+	}
+
+	sb.WriteByte(' ')
+	if g.whiteToMove {
+		sb.WriteByte('w')
+	} else {
+		sb.WriteByte('b')
+	}
+
+	sb.WriteByte(' ')
+	rights := ""
+	if g.castleWK {
+		rights += "K"
+	}
+	if g.castleWQ {
+		rights += "Q"
+	}
+	if g.castleBK {
+		rights += "k"
+	}
+	if g.castleBQ {
+		rights += "q"
+	}
+	if rights == "" {
+		rights = "-"
+	}
+	sb.WriteString(rights)
+
+	sb.WriteByte(' ')
+	if g.epTargetY >= 0 {
+		sb.WriteByte(byte('a' + g.epTargetX))
+		sb.WriteByte(byte('0' + (8 - g.epTargetY)))
+	} else {
+		sb.WriteByte('-')
+	}
+
+	fmt.Fprintf(&sb, " %d %d", g.halfmove, g.fullmove)
+	return sb.String()
+}
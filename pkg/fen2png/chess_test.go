@@ -0,0 +1,43 @@
+package fen2png
+
+import "testing"
+
+// TestApplyCastleThroughCheck builds a position where White's kingside
+// rook and king are free to castle but a Black rook on the f-file attacks
+// the f1 square, and checks that applyCastle rejects the move rather
+// than moving the king through check.
+func TestApplyCastleThroughCheck(t *testing.T) {
+	g := newGameState()
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			g.board[y][x] = ' '
+		}
+	}
+	g.board[7][4] = 'K'
+	g.board[7][7] = 'R'
+	g.board[0][4] = 'k'
+	g.board[0][5] = 'r' // f8 rook attacks f1 along the f-file
+
+	if err := g.applyCastle(true); err == nil {
+		t.Fatalf("applyCastle(true) = nil error, want rejection for castling through check")
+	}
+}
+
+func TestApplyCastleKingside(t *testing.T) {
+	g := newGameState()
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			g.board[y][x] = ' '
+		}
+	}
+	g.board[7][4] = 'K'
+	g.board[7][7] = 'R'
+	g.board[0][4] = 'k'
+
+	if err := g.applyCastle(true); err != nil {
+		t.Fatalf("applyCastle(true) = %v, want no error", err)
+	}
+	if g.board[7][6] != 'K' || g.board[7][5] != 'R' {
+		t.Fatalf("applyCastle(true) board = %v, king/rook not in expected squares", g.board)
+	}
+}
@@ -0,0 +1,75 @@
+package fen2png
+
+import (
+	"fmt"
+	"strings"
+)
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// Board is an 8x8 grid of piece characters, as used in FEN notation, with
+// ' ' marking an empty square. board[0] is rank 8 and board[7] is rank 1,
+// unless the position has been flipped.
+type Board [8][8]rune
+
+// DecodeFEN decodes the first two fields of a FEN record into a Board and
+// whether it should be rendered flipped (from Options.Flip, or from the
+// side to move when Options.AutoFlip is set).
+func DecodeFEN(fen string, opts Options) (board Board, flip bool, err error) {
+	fields := strings.Fields(fen)
+	if len(fields) == 0 {
+		return board, false, fmt.Errorf("empty FEN")
+	}
+	if opts.AutoFlip && len(fields) < 2 {
+		return board, false, fmt.Errorf("the second field of FEN is required for auto-flip")
+	}
+	flip = opts.Flip || (opts.AutoFlip && fields[1] == "b")
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return board, false, fmt.Errorf("%d ranks in FEN", len(ranks))
+	}
+
+	font := opts.font()
+	for y, rank := range ranks {
+		x := 0
+		for _, piece := range rank {
+			if piece >= '1' && piece <= '8' {
+				for i := 0; i < int(piece)-'0'; i++ {
+					if x < 8 {
+						board[y][x] = ' '
+					}
+					x++
+				}
+			} else if _, _, ok := font.Piece(piece); ok {
+				if x < 8 {
+					board[y][x] = piece
+				}
+				x++
+			} else {
+				return board, false, fmt.Errorf("unknown piece %q in FEN", piece)
+			}
+		}
+		if x != 8 {
+			return board, false, fmt.Errorf("%d files in FEN at rank %q", x, rank)
+		}
+	}
+
+	if flip {
+		for y := 0; y < 4; y++ {
+			board[y], board[7-y] = board[7-y], board[y]
+		}
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 4; x++ {
+				board[y][x], board[y][7-x] = board[y][7-x], board[y][x]
+			}
+		}
+	}
+	return board, flip, nil
+}
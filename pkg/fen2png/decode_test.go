@@ -0,0 +1,36 @@
+package fen2png
+
+import "testing"
+
+func TestDecodeFENMalformedRanks(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"digit overflow", "8p/8/8/8/8/8/8/8 w - - 0 1"},
+		{"piece overflow", "pppppppppp/8/8/8/8/8/8/8 w - - 0 1"},
+		{"too few files", "7/8/8/8/8/8/8/8 w - - 0 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeFEN(tt.fen, DefaultOptions())
+			if err == nil {
+				t.Fatalf("DecodeFEN(%q) = nil error, want a files-count error", tt.fen)
+			}
+		})
+	}
+}
+
+func TestDecodeFENValid(t *testing.T) {
+	const fen = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	board, flip, err := DecodeFEN(fen, DefaultOptions())
+	if err != nil {
+		t.Fatalf("DecodeFEN(%q) = %v, want no error", fen, err)
+	}
+	if flip {
+		t.Fatalf("DecodeFEN(%q) flip = true, want false", fen)
+	}
+	if board[0][0] != 'r' || board[7][7] != 'R' {
+		t.Fatalf("DecodeFEN(%q) board = %v, corners not decoded as expected", fen, board)
+	}
+}
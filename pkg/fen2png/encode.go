@@ -0,0 +1,28 @@
+package fen2png
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// encodeImage writes diagram to w in the given raster format. svg is
+// handled separately by renderSVG and never reaches this function.
+func encodeImage(w io.Writer, diagram image.Image, format string) error {
+	switch format {
+	case "png":
+		return png.Encode(w, diagram)
+	case "bmp":
+		return bmp.Encode(w, diagram)
+	case "tiff":
+		return tiff.Encode(w, diagram, nil)
+	case "webp":
+		return encodeWebP(w, diagram)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !webp
+
+package fen2png
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// encodeWebP without the webp build tag reports that support was not
+// compiled in, instead of silently failing the CGO-free default build.
+func encodeWebP(w io.Writer, diagram image.Image) error {
+	return fmt.Errorf("webp support not built in (rebuild with -tags webp)")
+}
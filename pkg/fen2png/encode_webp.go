@@ -0,0 +1,17 @@
+//go:build webp
+
+package fen2png
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+// encodeWebP is only compiled in with -tags webp, since
+// github.com/chai2010/webp requires CGO and the default build stays a
+// CGO-free static binary.
+func encodeWebP(w io.Writer, diagram image.Image) error {
+	return webp.Encode(w, diagram, &webp.Options{Lossless: true})
+}
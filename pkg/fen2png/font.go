@@ -0,0 +1,103 @@
+// Package fen2png renders chess diagrams from FEN records.
+package fen2png
+
+import _ "embed"
+
+// Font supplies the TrueType outlines and code-point mapping a renderer
+// needs to draw a diagram. Merida is the built-in implementation; set
+// Options.Font to draw with another chess TTF instead, and RegisterFont to
+// make it available by name.
+type Font interface {
+	// TTF returns the raw TrueType font bytes.
+	TTF() []byte
+	// Piece returns the code points for piece p (a FEN piece letter, or
+	// ' ' for an empty square) on a light and dark square, respectively.
+	Piece(p rune) (light, dark rune, ok bool)
+	// Number returns the code point for the rank label at index i, where
+	// i=0 is rank 8 and i=7 is rank 1.
+	Number(i int) rune
+	// Letter returns the code point for the file label at index i, where
+	// i=0 is file a and i=7 is file h.
+	Letter(i int) rune
+	TopLeftCorner() rune
+	TopSide() rune
+	TopRightCorner() rune
+	LeftSide() rune
+	RightSide() rune
+	BottomLeftCorner() rune
+	BottomSide() rune
+	BottomRightCorner() rune
+}
+
+//go:embed merida.ttf
+var meridaTTF []byte
+
+type meridaFont struct {
+	pieces  map[rune][2]rune
+	numbers [8]rune
+	letters [8]rune
+}
+
+// Merida is the default Font, a pure piece-silhouette chess TTF.
+var Merida Font = &meridaFont{
+	pieces: map[rune][2]rune{
+		' ': {'\uf020', '\uf02b'}, // No piece on light and dark squares
+		'R': {'\uf072', '\uf052'}, // White rook on light and dark squares
+		'N': {'\uf06e', '\uf04e'}, // White knight on light and dark squares
+		'B': {'\uf062', '\uf042'}, // White bishop on light and dark squares
+		'Q': {'\uf071', '\uf051'}, // White queen on light and dark squares
+		'K': {'\uf06b', '\uf04b'}, // White king on light and dark squares
+		'P': {'\uf070', '\uf050'}, // White pawn on light and dark squares
+		'r': {'\uf074', '\uf054'}, // Black rook on light and dark squares
+		'n': {'\uf06d', '\uf04d'}, // Black knight on light and dark squares
+		'b': {'\uf076', '\uf056'}, // Black bishop on light and dark squares
+		'q': {'\uf077', '\uf057'}, // Black queen on light and dark squares
+		'k': {'\uf06c', '\uf04c'}, // Black king on light and dark squares
+		'p': {'\uf06f', '\uf04f'}, // Black pawn on light and dark squares
+		'd': {'\uf02e', '\uf03a'}, // Black dot on light and dark squares
+		'x': {'\uf078', '\uf058'}, // Black cross on light and dark squares
+	},
+	numbers: [8]rune{
+		'\uf0c7', '\uf0c6', '\uf0c5', '\uf0c4', // 8, 7, 6, 5
+		'\uf0c3', '\uf0c2', '\uf0c1', '\uf0c0', // 4, 3, 2, 1
+	},
+	letters: [8]rune{
+		'\uf0c8', '\uf0c9', '\uf0ca', '\uf0cb', // a, b, c, d
+		'\uf0cc', '\uf0cd', '\uf0ce', '\uf0cf', // e, f, g, h
+	},
+}
+
+func (f *meridaFont) TTF() []byte { return meridaTTF }
+
+func (f *meridaFont) Piece(p rune) (light, dark rune, ok bool) {
+	r, ok := f.pieces[p]
+	return r[0], r[1], ok
+}
+
+func (f *meridaFont) Number(i int) rune { return f.numbers[i] }
+func (f *meridaFont) Letter(i int) rune { return f.letters[i] }
+
+func (f *meridaFont) TopLeftCorner() rune     { return '\uf031' }
+func (f *meridaFont) TopSide() rune           { return '\uf032' }
+func (f *meridaFont) TopRightCorner() rune    { return '\uf033' }
+func (f *meridaFont) LeftSide() rune          { return '\uf034' }
+func (f *meridaFont) RightSide() rune         { return '\uf035' }
+func (f *meridaFont) BottomLeftCorner() rune  { return '\uf037' }
+func (f *meridaFont) BottomSide() rune        { return '\uf038' }
+func (f *meridaFont) BottomRightCorner() rune { return '\uf039' }
+
+var fontRegistry = map[string]Font{
+	"merida": Merida,
+}
+
+// RegisterFont makes f available under name for callers that select fonts
+// by name (e.g. a --font CLI flag), alongside the built-in Merida font.
+func RegisterFont(name string, f Font) {
+	fontRegistry[name] = f
+}
+
+// GetFont looks up a font registered with RegisterFont.
+func GetFont(name string) (Font, bool) {
+	f, ok := fontRegistry[name]
+	return f, ok
+}
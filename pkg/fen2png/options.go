@@ -0,0 +1,124 @@
+package fen2png
+
+import (
+	"image/color"
+	"strings"
+)
+
+// Options controls how Render and RenderTo draw a diagram.
+type Options struct {
+	Size       int
+	Background color.Color
+	Foreground color.Color
+	// LightSquare and DarkSquare override Background for the two square
+	// colors. PieceWhite and PieceBlack override Foreground for pieces,
+	// chosen by the case of the FEN piece letter. PieceOutline, if set,
+	// draws each piece a second time offset by 1px before the main draw
+	// to produce a stroke. Theme fills in any of these left unset.
+	LightSquare, DarkSquare color.Color
+	PieceWhite, PieceBlack  color.Color
+	PieceOutline            color.Color
+	Theme                   string
+	Grayscale               bool
+	Coordinates             bool
+	Flip                    bool
+	AutoFlip                bool
+	// Format selects the output format: "svg", "png", "bmp", "tiff", or
+	// "webp". If empty, RenderTo infers it from the output file name
+	// passed to it, falling back to "png".
+	Format string
+	// Font supplies the glyph outlines to draw. Defaults to Merida.
+	Font Font
+	// Annotations overlays highlighted squares, arrows, and a check
+	// indicator on top of the diagram.
+	Annotations Annotations
+}
+
+// DefaultOptions returns the Options RenderTo uses when none are given: a
+// 400x400 diagram, black pieces on a white background, drawn with Merida.
+func DefaultOptions() Options {
+	return Options{
+		Size:       400,
+		Background: color.White,
+		Foreground: color.Black,
+	}
+}
+
+func (opts Options) font() Font {
+	if opts.Font != nil {
+		return opts.Font
+	}
+	return Merida
+}
+
+func (opts Options) background() color.Color {
+	if opts.Background != nil {
+		return opts.Background
+	}
+	return color.White
+}
+
+func (opts Options) foreground() color.Color {
+	if opts.Foreground != nil {
+		return opts.Foreground
+	}
+	return color.Black
+}
+
+// resolvedTheme is the fully-resolved set of colors Render and renderSVG
+// draw with: an explicit field wins, then the named Theme, then the
+// single Background/Foreground colors, then the package defaults.
+type resolvedTheme struct {
+	lightSquare, darkSquare color.Color
+	pieceWhite, pieceBlack  color.Color
+	pieceOutline            color.Color
+}
+
+func (opts Options) resolveTheme() resolvedTheme {
+	preset := Themes[opts.Theme]
+	pick := func(explicit, fromTheme, fallback color.Color) color.Color {
+		switch {
+		case explicit != nil:
+			return explicit
+		case fromTheme != nil:
+			return fromTheme
+		default:
+			return fallback
+		}
+	}
+	return resolvedTheme{
+		lightSquare:  pick(opts.LightSquare, preset.LightSquare, opts.background()),
+		darkSquare:   pick(opts.DarkSquare, preset.DarkSquare, opts.background()),
+		pieceWhite:   pick(opts.PieceWhite, preset.PieceWhite, opts.foreground()),
+		pieceBlack:   pick(opts.PieceBlack, preset.PieceBlack, opts.foreground()),
+		pieceOutline: pick(opts.PieceOutline, preset.PieceOutline, nil),
+	}
+}
+
+func (opts Options) size() int {
+	if opts.Size > 0 {
+		return opts.Size
+	}
+	return 400
+}
+
+// resolveFormat returns the format to render in: an explicit Format takes
+// precedence, otherwise it is inferred from outputFile's extension,
+// falling back to "png".
+func (opts Options) resolveFormat(outputFile string) string {
+	if opts.Format != "" {
+		return opts.Format
+	}
+	switch {
+	case strings.HasSuffix(outputFile, ".svg"):
+		return "svg"
+	case strings.HasSuffix(outputFile, ".bmp"):
+		return "bmp"
+	case strings.HasSuffix(outputFile, ".tiff"), strings.HasSuffix(outputFile, ".tif"):
+		return "tiff"
+	case strings.HasSuffix(outputFile, ".webp"):
+		return "webp"
+	default:
+		return "png"
+	}
+}
@@ -0,0 +1,45 @@
+package fen2png
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	pgnComment          = regexp.MustCompile(`\{[^}]*\}`)
+	pgnMoveNumberPrefix = regexp.MustCompile(`^\d+\.+`)
+	pgnResult           = regexp.MustCompile(`^(1-0|0-1|1/2-1/2|\*)$`)
+)
+
+// FENsFromPGN parses the moves of a single PGN game (tag pairs plus
+// movetext; only one game is supported) and returns the FEN after every
+// ply, in order. The SAN parser is a small built-in one: enough to
+// disambiguate moves and apply piece movement, castling, en passant, and
+// promotion, not a full PGN/game-database implementation.
+func FENsFromPGN(pgn string) ([]string, error) {
+	var movetext strings.Builder
+	for _, line := range strings.Split(pgn, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			continue
+		}
+		movetext.WriteString(line)
+		movetext.WriteByte(' ')
+	}
+	body := pgnComment.ReplaceAllString(movetext.String(), " ")
+
+	game := newGameState()
+	var fens []string
+	for _, tok := range strings.Fields(body) {
+		tok = pgnMoveNumberPrefix.ReplaceAllString(tok, "")
+		if tok == "" || pgnResult.MatchString(tok) {
+			continue
+		}
+		if err := game.applySAN(tok); err != nil {
+			return nil, fmt.Errorf("move %d (%q): %w", len(fens)+1, tok, err)
+		}
+		fens = append(fens, game.fen())
+	}
+	return fens, nil
+}
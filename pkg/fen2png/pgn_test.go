@@ -0,0 +1,34 @@
+package fen2png
+
+import "testing"
+
+func TestFENsFromPGNKnightDisambiguation(t *testing.T) {
+	const pgn = `1. Nf3 h6 2. d4 h5 3. Nfd2 h4`
+	fens, err := FENsFromPGN(pgn)
+	if err != nil {
+		t.Fatalf("FENsFromPGN(%q) = %v, want no error", pgn, err)
+	}
+	last := fens[len(fens)-1]
+	const want = "rnbqkbnr/ppppppp1/8/8/3P3p/8/PPPNPPPP/RNBQKB1R w KQkq - 0 4"
+	if last != want {
+		t.Fatalf("FENsFromPGN(%q) last FEN = %q, want %q", pgn, last, want)
+	}
+
+	const ambiguous = `1. Nf3 h6 2. d4 h5 3. Nd2 h4`
+	if _, err := FENsFromPGN(ambiguous); err == nil {
+		t.Fatalf("FENsFromPGN(%q) = nil error, want ambiguous move error", ambiguous)
+	}
+}
+
+func TestFENsFromPGNEnPassant(t *testing.T) {
+	const pgn = `1. e4 a6 2. e5 d5 3. exd6`
+	fens, err := FENsFromPGN(pgn)
+	if err != nil {
+		t.Fatalf("FENsFromPGN(%q) = %v, want no error", pgn, err)
+	}
+	last := fens[len(fens)-1]
+	const want = "rnbqkbnr/1pp1pppp/p2P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"
+	if last != want {
+		t.Fatalf("FENsFromPGN(%q) last FEN = %q, want %q", pgn, last, want)
+	}
+}
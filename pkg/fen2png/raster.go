@@ -0,0 +1,167 @@
+package fen2png
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"unicode"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Render decodes fen and draws it into a raster image using freetype,
+// honoring opts.Size, its theme colors (see Options.resolveTheme),
+// opts.Grayscale, opts.Coordinates, opts.Flip/AutoFlip, and opts.Font. It
+// does not consult opts.Format: svg diagrams are text, not an
+// image.Image, and are produced by RenderTo directly.
+//
+// Each square is drawn in its own pass so that light and dark squares,
+// and white and black pieces, can use distinct colors: fill the square,
+// then (if PieceOutline is set) the piece offset by 1px in the outline
+// color, then the piece itself.
+func Render(fen string, opts Options) (image.Image, error) {
+	board, flip, err := DecodeFEN(fen, opts)
+	if err != nil {
+		return nil, err
+	}
+	theme := opts.resolveTheme()
+	highlights, arrows, err := opts.Annotations.resolve(fen, board, flip)
+	if err != nil {
+		return nil, err
+	}
+
+	size := opts.size()
+	var diagram draw.Image
+	if opts.Grayscale {
+		diagram = image.NewGray(image.Rect(0, 0, size, size))
+	} else {
+		diagram = image.NewNRGBA(image.Rect(0, 0, size, size))
+	}
+	draw.Draw(diagram, diagram.Bounds(), image.NewUniform(theme.lightSquare), image.Point{}, draw.Src)
+
+	fnt := opts.font()
+	ttf, err := truetype.Parse(fnt.TTF())
+	if err != nil {
+		return nil, err
+	}
+	ctx := freetype.NewContext()
+	ctx.SetFont(ttf)
+	cell := float64(size) / 10.0
+	ctx.SetFontSize(cell)
+	ctx.SetHinting(font.HintingNone)
+	ctx.SetDst(diagram)
+	ctx.SetClip(diagram.Bounds())
+
+	pos := func(cells float64) fixed.Int26_6 { return fixed.Int26_6(cells * cell * 64) }
+	drawRune := func(col, row int, r rune, src color.Color) error {
+		ctx.SetSrc(image.NewUniform(src))
+		pt := fixed.Point26_6{X: pos(float64(col)), Y: pos(float64(row + 1))}
+		_, err := ctx.DrawString(string(r), pt)
+		return err
+	}
+
+	// Border frame.
+	if err := drawRune(0, 0, fnt.TopLeftCorner(), theme.pieceBlack); err != nil {
+		return nil, err
+	}
+	for x := 0; x < 8; x++ {
+		if err := drawRune(x+1, 0, fnt.TopSide(), theme.pieceBlack); err != nil {
+			return nil, err
+		}
+	}
+	if err := drawRune(9, 0, fnt.TopRightCorner(), theme.pieceBlack); err != nil {
+		return nil, err
+	}
+	if err := drawRune(0, 9, fnt.BottomLeftCorner(), theme.pieceBlack); err != nil {
+		return nil, err
+	}
+	for x := 0; x < 8; x++ {
+		var r rune
+		if opts.Coordinates {
+			if flip {
+				r = fnt.Letter(7 - x)
+			} else {
+				r = fnt.Letter(x)
+			}
+		} else {
+			r = fnt.BottomSide()
+		}
+		if err := drawRune(x+1, 9, r, theme.pieceBlack); err != nil {
+			return nil, err
+		}
+	}
+	if err := drawRune(9, 9, fnt.BottomRightCorner(), theme.pieceBlack); err != nil {
+		return nil, err
+	}
+
+	for y := 0; y < 8; y++ {
+		var r rune
+		if opts.Coordinates {
+			if flip {
+				r = fnt.Number(7 - y)
+			} else {
+				r = fnt.Number(y)
+			}
+		} else {
+			r = fnt.LeftSide()
+		}
+		if err := drawRune(0, y+1, r, theme.pieceBlack); err != nil {
+			return nil, err
+		}
+		if err := drawRune(9, y+1, fnt.RightSide(), theme.pieceBlack); err != nil {
+			return nil, err
+		}
+	}
+
+	// Squares and pieces.
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			squareColor := theme.lightSquare
+			if (x+y)%2 != 0 {
+				squareColor = theme.darkSquare
+			}
+			square := image.Rect(int((float64(x)+1)*cell), int((float64(y)+1)*cell), int((float64(x)+2)*cell), int((float64(y)+2)*cell))
+			draw.Draw(diagram, square, image.NewUniform(squareColor), image.Point{}, draw.Src)
+			if hl, ok := highlights[[2]int{y, x}]; ok {
+				draw.Draw(diagram, square, image.NewUniform(hl), image.Point{}, draw.Over)
+			}
+
+			piece := board[y][x]
+			if piece == ' ' {
+				continue
+			}
+			light, dark, _ := fnt.Piece(piece)
+			r := light
+			if (x+y)%2 != 0 {
+				r = dark
+			}
+			pieceColor := theme.pieceBlack
+			if unicode.IsUpper(piece) {
+				pieceColor = theme.pieceWhite
+			}
+
+			if theme.pieceOutline != nil {
+				ctx.SetSrc(image.NewUniform(theme.pieceOutline))
+				pt := fixed.Point26_6{X: pos(float64(x+1)) + 64, Y: pos(float64(y+2)) + 64}
+				if _, err := ctx.DrawString(string(r), pt); err != nil {
+					return nil, err
+				}
+			}
+			if err := drawRune(x+1, y+1, r, pieceColor); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	arrowColor := opts.Annotations.arrowColor()
+	for _, a := range arrows {
+		x0, y0 := (float64(a.fromX)+1.5)*cell, (float64(a.fromY)+1.5)*cell
+		x1, y1 := (float64(a.toX)+1.5)*cell, (float64(a.toY)+1.5)*cell
+		drawArrow(diagram, x0, y0, x1, y1, cell, arrowColor)
+	}
+
+	return diagram, nil
+}
@@ -0,0 +1,39 @@
+package fen2png
+
+import (
+	"context"
+	"io"
+)
+
+// RenderTo decodes fen and writes a complete diagram to w, in the format
+// given by opts.Format or, if empty, inferred from outputFile's
+// extension (falling back to png). outputFile is only consulted for
+// format inference; RenderTo itself never opens or creates a file.
+//
+// RenderTo checks ctx for cancellation before doing any work, so it is
+// safe to call from a server handler bound to a request context.
+func RenderTo(ctx context.Context, w io.Writer, fen string, outputFile string, opts Options) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	format := opts.resolveFormat(outputFile)
+	if format == "svg" {
+		board, flip, err := DecodeFEN(fen, opts)
+		if err != nil {
+			return err
+		}
+		svg, err := renderSVG(fen, board, flip, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, svg)
+		return err
+	}
+
+	diagram, err := Render(fen, opts)
+	if err != nil {
+		return err
+	}
+	return encodeImage(w, diagram, format)
+}
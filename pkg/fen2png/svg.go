@@ -0,0 +1,242 @@
+package fen2png
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// svgFont decodes glyph outlines from a TrueType font into SVG path data
+// using golang.org/x/image/font/sfnt, rather than rasterizing them through
+// freetype. Paths are cached per rune since the same glyph (e.g. an empty
+// square or a pawn) is usually drawn many times on one board.
+type svgFont struct {
+	font       *sfnt.Font
+	buf        sfnt.Buffer
+	unitsPerEm fixed.Int26_6
+	pathCache  map[rune]string
+}
+
+func newSVGFont(ttf []byte) (*svgFont, error) {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	unitsPerEm := fixed.Int26_6(f.UnitsPerEm())
+	return &svgFont{
+		font:       f,
+		unitsPerEm: unitsPerEm,
+		pathCache:  make(map[rune]string),
+	}, nil
+}
+
+// glyphPath returns SVG path data ("M.. L.. Q.. Z") for r scaled so that
+// one em equals size pixels, with the font's y-up coordinates flipped to
+// SVG's y-down axis and the origin placed at the glyph's baseline.
+func (sf *svgFont) glyphPath(r rune, size float64) (string, error) {
+	if p, ok := sf.pathCache[r]; ok {
+		return p, nil
+	}
+
+	idx, err := sf.font.GlyphIndex(&sf.buf, r)
+	if err != nil {
+		return "", err
+	}
+	segments, err := sf.font.LoadGlyph(&sf.buf, idx, sf.unitsPerEm, nil)
+	if err != nil {
+		return "", err
+	}
+
+	scale := size / float64(sf.unitsPerEm)
+	point := func(p fixed.Point26_6) (float64, float64) {
+		return float64(p.X) * scale, -float64(p.Y) * scale
+	}
+
+	var path strings.Builder
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			x, y := point(seg.Args[0])
+			fmt.Fprintf(&path, "M%.2f,%.2f ", x, y)
+		case sfnt.SegmentOpLineTo:
+			x, y := point(seg.Args[0])
+			fmt.Fprintf(&path, "L%.2f,%.2f ", x, y)
+		case sfnt.SegmentOpQuadTo:
+			cx, cy := point(seg.Args[0])
+			x, y := point(seg.Args[1])
+			fmt.Fprintf(&path, "Q%.2f,%.2f %.2f,%.2f ", cx, cy, x, y)
+		case sfnt.SegmentOpCubeTo:
+			c1x, c1y := point(seg.Args[0])
+			c2x, c2y := point(seg.Args[1])
+			x, y := point(seg.Args[2])
+			fmt.Fprintf(&path, "C%.2f,%.2f %.2f,%.2f %.2f,%.2f ", c1x, c1y, c2x, c2y, x, y)
+		}
+	}
+	path.WriteString("Z")
+
+	s := path.String()
+	sf.pathCache[r] = s
+	return s, nil
+}
+
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// opacity returns c's alpha channel as a 0..1 fraction, for SVG's
+// fill-opacity/stroke-opacity attributes.
+func opacity(c color.Color) float64 {
+	_, _, _, a := c.RGBA()
+	return float64(a) / 0xffff
+}
+
+// renderSVG renders board as an SVG document: the same bordered frame as
+// Render, drawn with <path> from the font's corner/side/label glyphs,
+// <rect> squares for the 8x8 grid, and a <path> per piece built from the
+// decoded font glyph outlines. Unlike the raster pipeline, the result
+// scales to any size without re-rasterizing.
+func renderSVG(fen string, board Board, flip bool, opts Options) (string, error) {
+	fnt := opts.font()
+	sf, err := newSVGFont(fnt.TTF())
+	if err != nil {
+		return "", err
+	}
+	size := opts.size()
+	cell := float64(size) / 10.0
+	theme := opts.resolveTheme()
+	bg := hexColor(theme.lightSquare)
+	highlights, arrows, err := opts.Annotations.resolve(fen, board, flip)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		size, size, size, size)
+	fmt.Fprintf(&out, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", size, size, bg)
+
+	// glyph draws the font glyph r as a <path>, positioned the same way
+	// Render positions it with freetype: baseline at (col, row+1) cells.
+	glyph := func(col, row int, r rune, src color.Color) error {
+		path, err := sf.glyphPath(r, cell)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, "<path transform=\"translate(%.2f,%.2f)\" d=\"%s\" fill=\"%s\"/>\n",
+			float64(col)*cell, float64(row+1)*cell, path, hexColor(src))
+		return nil
+	}
+
+	// Border frame, matching Render's raster border: corner glyphs, then
+	// either the plain side glyphs or, with Coordinates set, file/rank
+	// labels drawn into the same border cells.
+	if err := glyph(0, 0, fnt.TopLeftCorner(), theme.pieceBlack); err != nil {
+		return "", err
+	}
+	for x := 0; x < 8; x++ {
+		if err := glyph(x+1, 0, fnt.TopSide(), theme.pieceBlack); err != nil {
+			return "", err
+		}
+	}
+	if err := glyph(9, 0, fnt.TopRightCorner(), theme.pieceBlack); err != nil {
+		return "", err
+	}
+	if err := glyph(0, 9, fnt.BottomLeftCorner(), theme.pieceBlack); err != nil {
+		return "", err
+	}
+	for x := 0; x < 8; x++ {
+		r := fnt.BottomSide()
+		if opts.Coordinates {
+			if flip {
+				r = fnt.Letter(7 - x)
+			} else {
+				r = fnt.Letter(x)
+			}
+		}
+		if err := glyph(x+1, 9, r, theme.pieceBlack); err != nil {
+			return "", err
+		}
+	}
+	if err := glyph(9, 9, fnt.BottomRightCorner(), theme.pieceBlack); err != nil {
+		return "", err
+	}
+	for y := 0; y < 8; y++ {
+		r := fnt.LeftSide()
+		if opts.Coordinates {
+			if flip {
+				r = fnt.Number(7 - y)
+			} else {
+				r = fnt.Number(y)
+			}
+		}
+		if err := glyph(0, y+1, r, theme.pieceBlack); err != nil {
+			return "", err
+		}
+		if err := glyph(9, y+1, fnt.RightSide(), theme.pieceBlack); err != nil {
+			return "", err
+		}
+	}
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			squareColor := theme.lightSquare
+			if (x+y)%2 != 0 {
+				squareColor = theme.darkSquare
+			}
+			fmt.Fprintf(&out, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"%s\"/>\n",
+				(float64(x)+1)*cell, (float64(y)+1)*cell, cell, cell, hexColor(squareColor))
+			if hl, ok := highlights[[2]int{y, x}]; ok {
+				fmt.Fprintf(&out, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"%s\" fill-opacity=\"%.2f\"/>\n",
+					(float64(x)+1)*cell, (float64(y)+1)*cell, cell, cell, hexColor(hl), opacity(hl))
+			}
+		}
+	}
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if board[y][x] == ' ' {
+				continue
+			}
+			light, dark, _ := fnt.Piece(board[y][x])
+			r := light
+			if (x+y)%2 != 0 {
+				r = dark
+			}
+			path, err := sf.glyphPath(r, cell)
+			if err != nil {
+				return "", err
+			}
+			pieceColor := theme.pieceBlack
+			if unicode.IsUpper(board[y][x]) {
+				pieceColor = theme.pieceWhite
+			}
+			tx, ty := (float64(x)+1)*cell, (float64(y)+2)*cell
+			if theme.pieceOutline != nil {
+				fmt.Fprintf(&out, "<path transform=\"translate(%.2f,%.2f)\" d=\"%s\" fill=\"%s\"/>\n",
+					tx+1, ty+1, path, hexColor(theme.pieceOutline))
+			}
+			fmt.Fprintf(&out, "<path transform=\"translate(%.2f,%.2f)\" d=\"%s\" fill=\"%s\"/>\n",
+				tx, ty, path, hexColor(pieceColor))
+		}
+	}
+
+	if len(arrows) > 0 {
+		arrowColor := opts.Annotations.arrowColor()
+		fmt.Fprintf(&out, "<defs><marker id=\"arrowhead\" viewBox=\"0 0 10 10\" refX=\"8\" refY=\"5\" markerWidth=\"%.2f\" markerHeight=\"%.2f\" orient=\"auto-start-reverse\"><polygon points=\"0,0 10,5 0,10\" fill=\"%s\" fill-opacity=\"%.2f\"/></marker></defs>\n",
+			cell*0.35, cell*0.35, hexColor(arrowColor), opacity(arrowColor))
+		for _, a := range arrows {
+			x0, y0 := (float64(a.fromX)+1.5)*cell, (float64(a.fromY)+1.5)*cell
+			x1, y1 := (float64(a.toX)+1.5)*cell, (float64(a.toY)+1.5)*cell
+			fmt.Fprintf(&out, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"%s\" stroke-opacity=\"%.2f\" stroke-width=\"%.2f\" marker-end=\"url(#arrowhead)\"/>\n",
+				x0, y0, x1, y1, hexColor(arrowColor), opacity(arrowColor), cell*0.12)
+		}
+	}
+
+	out.WriteString("</svg>\n")
+	return out.String(), nil
+}
@@ -0,0 +1,57 @@
+package fen2png
+
+import "image/color"
+
+// Theme is a named preset for square and piece colors, used as the
+// fallback for any Options color field left unset. See Themes for the
+// built-in presets.
+type Theme struct {
+	LightSquare, DarkSquare color.Color
+	PieceWhite, PieceBlack  color.Color
+	PieceOutline            color.Color
+}
+
+func rgb(hex uint32) color.RGBA {
+	return color.RGBA{
+		R: uint8(hex >> 16),
+		G: uint8(hex >> 8),
+		B: uint8(hex),
+		A: 0xff,
+	}
+}
+
+// Themes holds the built-in square/piece color presets selectable with
+// Options.Theme, modeled on the board colors of popular chess sites and
+// clients.
+var Themes = map[string]Theme{
+	"lichess-brown": {
+		LightSquare: rgb(0xf0d9b5),
+		DarkSquare:  rgb(0xb58863),
+		PieceWhite:  rgb(0xffffff),
+		PieceBlack:  rgb(0x000000),
+	},
+	"chess.com-green": {
+		LightSquare: rgb(0xeeeed2),
+		DarkSquare:  rgb(0x769656),
+		PieceWhite:  rgb(0xffffff),
+		PieceBlack:  rgb(0x000000),
+	},
+	"wikipedia": {
+		LightSquare: rgb(0xffce9e),
+		DarkSquare:  rgb(0xd18b47),
+		PieceWhite:  rgb(0xffffff),
+		PieceBlack:  rgb(0x000000),
+	},
+	"ic": {
+		LightSquare: rgb(0xc3c6be),
+		DarkSquare:  rgb(0x727c79),
+		PieceWhite:  rgb(0xffffff),
+		PieceBlack:  rgb(0x000000),
+	},
+	"blue": {
+		LightSquare: rgb(0xdee3e6),
+		DarkSquare:  rgb(0x8ca2ad),
+		PieceWhite:  rgb(0xffffff),
+		PieceBlack:  rgb(0x000000),
+	},
+}